@@ -0,0 +1,58 @@
+package googlegeocode
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the outcome of geocoding a single address as part of a
+// GeocodeBatch call.
+type BatchResult struct {
+	Address string
+	Results Results
+	Err     error
+}
+
+// GeocodeBatch geocodes addresses concurrently through g, with at most
+// concurrency requests in flight at once. A failure geocoding one address
+// is recorded in its BatchResult.Err rather than failing the whole batch.
+// If ctx is canceled, addresses that haven't started yet are reported with
+// ctx.Err() instead of being geocoded.
+//
+// Actual request pacing is left to g; GoogleProvider already paces itself
+// with a token-bucket rate limiter, so increasing concurrency here lets
+// callers queue work up to that limit instead of serializing it.
+func GeocodeBatch(ctx context.Context, g Geocoder, addresses []string, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(addresses))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				address := addresses[index]
+				if err := ctx.Err(); err != nil {
+					results[index] = BatchResult{Address: address, Err: err}
+					continue
+				}
+
+				r, err := g.Geocode(ctx, address)
+				results[index] = BatchResult{Address: address, Results: r, Err: err}
+			}
+		}()
+	}
+
+	for i := range addresses {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,53 @@
+package googlegeocode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// stubGeocoder returns Results carrying its input address as the status, so
+// tests can assert every address was geocoded without a network call.
+type stubGeocoder struct{}
+
+func (stubGeocoder) Geocode(ctx context.Context, address string, opts ...Option) (Results, error) {
+	return Results{Status: address}, nil
+}
+
+func (stubGeocoder) ReverseGeocode(ctx context.Context, lat, lng float64, opts ...Option) (Results, error) {
+	return Results{}, nil
+}
+
+func TestGeocodeBatch(t *testing.T) {
+	addresses := make([]string, 10)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("address-%d", i)
+	}
+
+	results := GeocodeBatch(context.Background(), stubGeocoder{}, addresses, 4)
+
+	if len(results) != len(addresses) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(addresses))
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("results[%d].Err = %v, want nil", i, r.Err)
+		}
+		if r.Results.Status != addresses[i] {
+			t.Errorf("results[%d].Results.Status = %q, want %q", i, r.Results.Status, addresses[i])
+		}
+	}
+}
+
+func TestGeocodeBatchCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := GeocodeBatch(ctx, stubGeocoder{}, []string{"a", "b"}, 2)
+
+	for i, r := range results {
+		if r.Err == nil {
+			t.Errorf("results[%d].Err = nil, want context.Canceled", i)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package googlegeocode
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the TTL applied to new cache entries when a Geocoder is
+// constructed with a Cache but no CacheTTL. 30 days matches the amount of
+// time Google's Geocoding API terms of service permit cached results to be
+// stored.
+const DefaultCacheTTL = 30 * 24 * time.Hour
+
+// Cache stores Results keyed by a normalized query, so that repeated
+// Geocode/ReverseGeocode calls for the same address or coordinates can skip
+// the network round trip. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the Results cached under key, and whether they were
+	// found and have not expired.
+	Get(key string) (Results, bool)
+	// Put stores r under key, expiring after ttl.
+	Put(key string, r Results, ttl time.Duration)
+}
+
+type cacheEntry struct {
+	Results   Results   `json:"results"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (e cacheEntry) expired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
+
+// memoryCache is an in-memory Cache bounded by entry count, evicting the
+// least recently used entry once capacity is exceeded.
+type memoryCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry cacheEntry
+}
+
+// NewMemoryCache returns a Cache that holds at most capacity entries,
+// evicting the least recently used entry to make room for new ones.
+func NewMemoryCache(capacity int) Cache {
+	return &memoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *memoryCache) Get(key string) (Results, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return Results{}, false
+	}
+
+	item := elem.Value.(*memoryCacheItem)
+	if item.entry.expired() {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return Results{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return item.entry.Results, true
+}
+
+func (c *memoryCache) Put(key string, r Results, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := cacheEntry{Results: r, ExpiresAt: time.Now().Add(ttl)}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*memoryCacheItem).entry = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	c.entries[key] = elem
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// fileCache is a Cache backed by a single JSON file, keyed by the SHA-256
+// hash of each query key. The whole file is read on construction and
+// rewritten on every Put.
+type fileCache struct {
+	mutex   sync.Mutex
+	path    string
+	entries map[string]cacheEntry
+}
+
+// NewFileCache returns a Cache backed by the JSON file at path, creating it
+// on first Put if it doesn't already exist.
+func NewFileCache(path string) (Cache, error) {
+	c := &fileCache{path: path, entries: make(map[string]cacheEntry)}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("googlegeocode: reading cache file: <%v>", err)
+	}
+	if len(content) == 0 {
+		return c, nil
+	}
+	if err := json.Unmarshal(content, &c.entries); err != nil {
+		return nil, fmt.Errorf("googlegeocode: parsing cache file: <%v>", err)
+	}
+
+	return c, nil
+}
+
+func (c *fileCache) Get(key string) (Results, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[hashCacheKey(key)]
+	if !ok || entry.expired() {
+		return Results{}, false
+	}
+	return entry.Results, true
+}
+
+func (c *fileCache) Put(key string, r Results, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[hashCacheKey(key)] = cacheEntry{Results: r, ExpiresAt: time.Now().Add(ttl)}
+
+	// Best effort: a failed write just means this entry isn't persisted.
+	if content, err := json.Marshal(c.entries); err == nil {
+		_ = os.WriteFile(c.path, content, 0o600)
+	}
+}
+
+func hashCacheKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachingGeocoder wraps a Geocoder with a Cache, keyed on the normalized
+// address or coordinates together with every Option passed to the call, so
+// that calls differing only in their Option arguments don't collide.
+type cachingGeocoder struct {
+	next  Geocoder
+	cache Cache
+	ttl   time.Duration
+}
+
+func (c *cachingGeocoder) Geocode(ctx context.Context, address string, opts ...Option) (Results, error) {
+	// Normalize case and whitespace so trivially different spellings of the
+	// same address share an entry.
+	normalized := strings.Join(strings.Fields(strings.ToLower(address)), " ")
+	key := "geocode:" + normalized + ":" + optsCacheKey(opts)
+	if r, ok := c.cache.Get(key); ok {
+		return r, nil
+	}
+
+	r, err := c.next.Geocode(ctx, address, opts...)
+	if err != nil {
+		return r, err
+	}
+	c.cache.Put(key, r, c.ttl)
+
+	return r, nil
+}
+
+func (c *cachingGeocoder) ReverseGeocode(ctx context.Context, lat, lng float64, opts ...Option) (Results, error) {
+	key := fmt.Sprintf("reverse:%v,%v:%s", lat, lng, optsCacheKey(opts))
+	if r, ok := c.cache.Get(key); ok {
+		return r, nil
+	}
+
+	r, err := c.next.ReverseGeocode(ctx, lat, lng, opts...)
+	if err != nil {
+		return r, err
+	}
+	c.cache.Put(key, r, c.ttl)
+
+	return r, nil
+}
+
+// optsCacheKey deterministically encodes the requestConfig resolved from
+// opts, so that two calls differing only in their Option arguments (e.g.
+// WithRegion("us") vs WithRegion("au")) don't share a cache entry. It
+// intentionally ignores a Geocoder's default language/region, since those
+// are constant for the lifetime of a cachingGeocoder and so can't cause a
+// collision.
+func optsCacheKey(opts []Option) string {
+	config := buildRequestConfig("", "", opts)
+
+	resultType := append([]string(nil), config.resultType...)
+	sort.Strings(resultType)
+	locationType := append([]string(nil), config.locationType...)
+	sort.Strings(locationType)
+
+	var bounds string
+	if config.bounds != nil {
+		bounds = config.bounds.String()
+	}
+
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+		componentsString(config.components),
+		bounds,
+		config.region,
+		config.language,
+		strings.Join(resultType, ","),
+		strings.Join(locationType, ","),
+	)
+}
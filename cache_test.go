@@ -0,0 +1,120 @@
+package googlegeocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheGetPut(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get(%q) found an entry before Put", "a")
+	}
+
+	want := Results{Status: "OK"}
+	c.Put("a", want, time.Minute)
+
+	got, ok := c.Get("a")
+	if !ok {
+		t.Fatalf("Get(%q) = not found, want found", "a")
+	}
+	if got.Status != want.Status {
+		t.Errorf("Get(%q) = %+v, want %+v", "a", got, want)
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Put("a", Results{Status: "OK"}, -time.Minute)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get(%q) found an entry that should have expired", "a")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Put("a", Results{Status: "a"}, time.Minute)
+	c.Put("b", Results{Status: "b"}, time.Minute)
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	c.Get("a")
+	c.Put("c", Results{Status: "c"}, time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(%q) found an entry, want it evicted", "b")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Get(%q) = not found, want found", "a")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Errorf("Get(%q) = not found, want found", "c")
+	}
+}
+
+func TestFileCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	c, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("NewFileCache(%q) error: %v", path, err)
+	}
+	c.Put("a", Results{Status: "OK"}, time.Minute)
+
+	reloaded, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("NewFileCache(%q) error on reload: %v", path, err)
+	}
+
+	got, ok := reloaded.Get("a")
+	if !ok {
+		t.Fatalf("Get(%q) = not found after reload, want found", "a")
+	}
+	if got.Status != "OK" {
+		t.Errorf("Get(%q) = %+v, want Status OK", "a", got)
+	}
+}
+
+// TestCachingGeocoderHonorsOptions verifies that the cachingGeocoder wrapper
+// installed by NewGeocoder doesn't return a cached result for a call that
+// differs only in its Option arguments.
+func TestCachingGeocoderHonorsOptions(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"features":[{"geometry":{"coordinates":[-74.0,40.7]},"properties":{"osm_id":1,"osm_value":"city","name":"New York"}}]}`))
+	}))
+	defer srv.Close()
+
+	g, err := NewGeocoder(Options{
+		Provider:   ProviderPhoton,
+		Endpoint:   srv.URL,
+		HTTPClient: srv.Client(),
+		Cache:      NewMemoryCache(10),
+	})
+	if err != nil {
+		t.Fatalf("NewGeocoder() error: %v", err)
+	}
+
+	if _, err := g.Geocode(context.Background(), "Springfield", WithRegion("us")); err != nil {
+		t.Fatalf("Geocode() error: %v", err)
+	}
+	if _, err := g.Geocode(context.Background(), "Springfield", WithRegion("au")); err != nil {
+		t.Fatalf("Geocode() error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (calls differing only by Option must not share a cache entry)", requests)
+	}
+
+	if _, err := g.Geocode(context.Background(), "Springfield", WithRegion("us")); err != nil {
+		t.Fatalf("Geocode() error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (repeating an identical call should still hit the cache)", requests)
+	}
+}
@@ -0,0 +1,108 @@
+package googlegeocode
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Sentinel errors matching the Google Geocode API's status codes. Check
+// which one a GeocodeError wraps with errors.Is, e.g.
+// errors.Is(err, ErrZeroResults).
+var (
+	// ErrZeroResults means the geocode succeeded but returned no results.
+	ErrZeroResults = errors.New("googlegeocode: ZERO_RESULTS")
+	// ErrRequestDenied means the request was denied, usually because of a
+	// missing, invalid, or restricted API key.
+	ErrRequestDenied = errors.New("googlegeocode: REQUEST_DENIED")
+	// ErrInvalidRequest means the request was missing a required
+	// parameter, such as address or latlng.
+	ErrInvalidRequest = errors.New("googlegeocode: INVALID_REQUEST")
+	// ErrUnknown means the request couldn't be processed due to a server
+	// error; it's retried once automatically, as Google recommends.
+	ErrUnknown = errors.New("googlegeocode: UNKNOWN_ERROR")
+	// ErrOverDailyLimit means the API key's daily request quota has been
+	// exceeded.
+	ErrOverDailyLimit = errors.New("googlegeocode: OVER_QUERY_LIMIT (daily quota)")
+	// ErrOverRateLimit means the short-term per-second request rate was
+	// exceeded.
+	ErrOverRateLimit = errors.New("googlegeocode: OVER_QUERY_LIMIT (rate)")
+)
+
+// GeocodeError reports a non-OK status from the Google Geocode API.
+type GeocodeError struct {
+	// Status is the raw "status" field from the API response.
+	Status string
+	// ErrorMessage is the raw "error_message" field, when the API provided one.
+	ErrorMessage string
+	// URL is the request URL, with the API key and signature redacted.
+	URL string
+
+	sentinel error
+}
+
+func (e *GeocodeError) Error() string {
+	if e.ErrorMessage != "" {
+		return fmt.Sprintf("googlegeocode: %s: %s (request: %s)", e.Status, e.ErrorMessage, e.URL)
+	}
+	return fmt.Sprintf("googlegeocode: %s (request: %s)", e.Status, e.URL)
+}
+
+// Unwrap lets errors.Is match GeocodeError against the sentinel Err*
+// values.
+func (e *GeocodeError) Unwrap() error {
+	return e.sentinel
+}
+
+// newGeocodeError builds a GeocodeError for status/errorMessage, selecting
+// the sentinel it wraps and redacting requestURL before storing it.
+func newGeocodeError(status, errorMessage, requestURL string) *GeocodeError {
+	sentinel := ErrUnknown
+	switch status {
+	case "ZERO_RESULTS":
+		sentinel = ErrZeroResults
+	case "REQUEST_DENIED":
+		sentinel = ErrRequestDenied
+	case "INVALID_REQUEST":
+		sentinel = ErrInvalidRequest
+	case "OVER_QUERY_LIMIT":
+		if isDailyQuotaMessage(errorMessage) {
+			sentinel = ErrOverDailyLimit
+		} else {
+			sentinel = ErrOverRateLimit
+		}
+	}
+
+	return &GeocodeError{
+		Status:       status,
+		ErrorMessage: errorMessage,
+		URL:          redactURL(requestURL),
+		sentinel:     sentinel,
+	}
+}
+
+// isDailyQuotaMessage reports whether errorMessage describes a daily quota
+// being exceeded, as opposed to a short-term rate overage.
+func isDailyQuotaMessage(errorMessage string) bool {
+	return strings.Contains(strings.ToLower(errorMessage), "daily")
+}
+
+// redactURL replaces the "key" and "signature" query parameters of rawURL
+// with "REDACTED", so request URLs are safe to log or include in errors.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	values := parsed.Query()
+	for _, param := range []string{"key", "signature"} {
+		if values.Get(param) != "" {
+			values.Set(param, "REDACTED")
+		}
+	}
+	parsed.RawQuery = values.Encode()
+
+	return parsed.String()
+}
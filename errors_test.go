@@ -0,0 +1,55 @@
+package googlegeocode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewGeocodeErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status       string
+		errorMessage string
+		want         error
+	}{
+		{status: "ZERO_RESULTS", want: ErrZeroResults},
+		{status: "REQUEST_DENIED", want: ErrRequestDenied},
+		{status: "INVALID_REQUEST", want: ErrInvalidRequest},
+		{status: "UNKNOWN_ERROR", want: ErrUnknown},
+		{status: "OVER_QUERY_LIMIT", want: ErrOverRateLimit},
+		{status: "OVER_QUERY_LIMIT", errorMessage: "You have exceeded your daily request quota.", want: ErrOverDailyLimit},
+	}
+
+	for _, tt := range tests {
+		err := newGeocodeError(tt.status, tt.errorMessage, "https://maps.googleapis.com/maps/api/geocode/json?key=secret")
+		if !errors.Is(err, tt.want) {
+			t.Errorf("newGeocodeError(%q, %q, ...) does not wrap %v", tt.status, tt.errorMessage, tt.want)
+		}
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "key",
+			in:   "https://maps.googleapis.com/maps/api/geocode/json?address=NYC&key=secret",
+			want: "https://maps.googleapis.com/maps/api/geocode/json?address=NYC&key=REDACTED",
+		},
+		{
+			name: "signature",
+			in:   "https://maps.googleapis.com/maps/api/geocode/json?address=NYC&client=id&signature=abc123",
+			want: "https://maps.googleapis.com/maps/api/geocode/json?address=NYC&client=id&signature=REDACTED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactURL(tt.in); got != tt.want {
+				t.Errorf("redactURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package googlegeocode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Geocoder converts addresses to geographic coordinates and back.
+type Geocoder interface {
+	// Geocode looks up the coordinates for address. opts biasing the
+	// result (WithComponents, WithBounds, WithRegion) are only honored by
+	// ProviderGoogle; WithLanguage is honored by every provider.
+	Geocode(ctx context.Context, address string, opts ...Option) (Results, error)
+	// ReverseGeocode looks up the address at the given coordinates. opts
+	// filtering the result (WithResultType, WithLocationType) are only
+	// honored by ProviderGoogle; WithLanguage is honored by every provider.
+	ReverseGeocode(ctx context.Context, lat, lng float64, opts ...Option) (Results, error)
+}
+
+// Provider identifies which geocoding service a Geocoder talks to.
+type Provider int
+
+const (
+	// ProviderGoogle uses the Google Geocode API. It requires an APIKey, or
+	// a ClientID and PrivateKey when AuthScheme is AuthSchemeWork.
+	ProviderGoogle Provider = iota
+	// ProviderPhoton uses a Photon server, such as https://photon.komoot.io
+	// or a self-hosted instance.
+	ProviderPhoton
+	// ProviderNominatim uses the OpenStreetMap Nominatim API.
+	ProviderNominatim
+)
+
+// AuthScheme selects how a GoogleProvider authenticates its requests.
+type AuthScheme int
+
+const (
+	// AuthSchemeAPIKey authenticates with APIKey. This is the default.
+	AuthSchemeAPIKey AuthScheme = iota
+	// AuthSchemeWork authenticates with ClientID and PrivateKey, signing
+	// each request as required by Google Maps APIs for Work (Premium Plan)
+	// credentials.
+	AuthSchemeWork
+)
+
+// Options configures the Geocoder returned by NewGeocoder.
+type Options struct {
+	// Provider selects which geocoding service to use. Defaults to
+	// ProviderGoogle.
+	Provider Provider
+
+	// AuthScheme selects how ProviderGoogle authenticates. Defaults to
+	// AuthSchemeAPIKey.
+	AuthScheme AuthScheme
+
+	// APIKey authenticates requests to Google. Required when Provider is
+	// ProviderGoogle and AuthScheme is AuthSchemeAPIKey.
+	APIKey string
+
+	// ClientID is the Google Maps APIs for Work client ID. Required when
+	// AuthScheme is AuthSchemeWork.
+	ClientID string
+
+	// PrivateKey is the URL-safe base64-encoded signing key issued alongside
+	// ClientID. Required when AuthScheme is AuthSchemeWork.
+	PrivateKey string
+
+	// Channel optionally identifies a sub-group of a Google Maps APIs for
+	// Work account for reporting purposes. Only used when AuthScheme is
+	// AuthSchemeWork.
+	Channel string
+
+	// Endpoint overrides the default service URL. Used by ProviderPhoton to
+	// point at a self-hosted instance, or by ProviderNominatim to point at a
+	// mirror.
+	Endpoint string
+
+	// HTTPClient is used to issue requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Language biases results toward a language, e.g. "en".
+	Language string
+
+	// Region biases results toward a ccTLD region, e.g. "us". Only used by
+	// ProviderGoogle.
+	Region string
+
+	// Cache, if set, is consulted before issuing a Geocode or
+	// ReverseGeocode request and populated with its result.
+	Cache Cache
+
+	// CacheTTL controls how long entries stay in Cache. Defaults to
+	// DefaultCacheTTL. Only used when Cache is set.
+	CacheTTL time.Duration
+
+	// RateLimitQPS caps how many requests per second ProviderGoogle will
+	// issue. Defaults to 50, Google's default per-project quota.
+	RateLimitQPS float64
+
+	// RateLimitBurst is how many requests ProviderGoogle can issue in a
+	// single instant before the rate limit applies. Defaults to 1.
+	RateLimitBurst int
+
+	// MaxRetries is how many additional attempts ProviderGoogle makes after
+	// a transient failure (a 5xx response, or a short-term OVER_QUERY_LIMIT)
+	// before giving up. Defaults to 3.
+	MaxRetries int
+}
+
+// NewGeocoder builds a Geocoder for the provider selected in opts.
+func NewGeocoder(opts Options) (Geocoder, error) {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	var (
+		geocoder Geocoder
+		err      error
+	)
+	switch opts.Provider {
+	case ProviderGoogle:
+		geocoder, err = newGoogleProvider(opts)
+	case ProviderPhoton:
+		geocoder = newPhotonProvider(opts)
+	case ProviderNominatim:
+		geocoder = newNominatimProvider(opts)
+	default:
+		return nil, fmt.Errorf("googlegeocode: unknown provider %d", opts.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Cache != nil {
+		ttl := opts.CacheTTL
+		if ttl == 0 {
+			ttl = DefaultCacheTTL
+		}
+		geocoder = &cachingGeocoder{next: geocoder, cache: opts.Cache, ttl: ttl}
+	}
+
+	return geocoder, nil
+}
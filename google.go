@@ -0,0 +1,341 @@
+package googlegeocode
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	googleGeocodeHost = "https://maps.googleapis.com"
+	googleGeocodePath = "/maps/api/geocode/json"
+	googleGeocodeURL  = googleGeocodeHost + googleGeocodePath
+
+	// defaultRateLimitQPS matches the default queries-per-second quota
+	// Google grants new Geocoding API projects.
+	defaultRateLimitQPS   = 50
+	defaultRateLimitBurst = 1
+	defaultMaxRetries     = 3
+
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+)
+
+// GoogleProvider queries the Google Geocode API.
+type GoogleProvider struct {
+	httpClient *http.Client
+	language   string
+	region     string
+
+	authScheme AuthScheme
+	apiKey     string
+	clientID   string
+	privateKey []byte
+	channel    string
+
+	limiter    *rate.Limiter
+	maxRetries int
+
+	mutex                sync.Mutex
+	queryLimitReached    bool
+	queryLimitExpiration time.Time
+}
+
+func newGoogleProvider(opts Options) (*GoogleProvider, error) {
+	g := &GoogleProvider{
+		httpClient: opts.HTTPClient,
+		language:   opts.Language,
+		region:     opts.Region,
+		authScheme: opts.AuthScheme,
+	}
+
+	switch opts.AuthScheme {
+	case AuthSchemeAPIKey:
+		if opts.APIKey == "" {
+			return nil, fmt.Errorf("googlegeocode: APIKey is required for AuthSchemeAPIKey")
+		}
+		g.apiKey = opts.APIKey
+	case AuthSchemeWork:
+		if opts.ClientID == "" || opts.PrivateKey == "" {
+			return nil, fmt.Errorf("googlegeocode: ClientID and PrivateKey are required for AuthSchemeWork")
+		}
+		privateKey, err := base64.URLEncoding.DecodeString(opts.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("googlegeocode: PrivateKey is not valid URL-safe base64: <%v>", err)
+		}
+		g.clientID = opts.ClientID
+		g.privateKey = privateKey
+		g.channel = opts.Channel
+	default:
+		return nil, fmt.Errorf("googlegeocode: unknown AuthScheme %d", opts.AuthScheme)
+	}
+
+	qps := opts.RateLimitQPS
+	if qps <= 0 {
+		qps = defaultRateLimitQPS
+	}
+	burst := opts.RateLimitBurst
+	if burst <= 0 {
+		burst = defaultRateLimitBurst
+	}
+	g.limiter = rate.NewLimiter(rate.Limit(qps), burst)
+
+	g.maxRetries = opts.MaxRetries
+	if g.maxRetries <= 0 {
+		g.maxRetries = defaultMaxRetries
+	}
+
+	return g, nil
+}
+
+// Geocode looks up the coordinates for address using the Google Geocode API.
+func (g *GoogleProvider) Geocode(ctx context.Context, address string, opts ...Option) (Results, error) {
+	values := url.Values{}
+	values.Set("address", address)
+
+	config := buildRequestConfig(g.language, g.region, opts)
+	if len(config.components) > 0 {
+		values.Set("components", componentsString(config.components))
+	}
+	if config.bounds != nil {
+		values.Set("bounds", config.bounds.String())
+	}
+
+	return g.query(ctx, values, config)
+}
+
+// ReverseGeocode looks up the address at the given coordinates using the
+// Google Geocode API.
+func (g *GoogleProvider) ReverseGeocode(ctx context.Context, lat, lng float64, opts ...Option) (Results, error) {
+	values := url.Values{}
+	values.Set("latlng", fmt.Sprintf("%v,%v", lat, lng))
+
+	config := buildRequestConfig(g.language, g.region, opts)
+	if len(config.resultType) > 0 {
+		values.Set("result_type", strings.Join(config.resultType, "|"))
+	}
+	if len(config.locationType) > 0 {
+		values.Set("location_type", strings.Join(config.locationType, "|"))
+	}
+
+	return g.query(ctx, values, config)
+}
+
+// query issues values against the Google Geocode API, applying config's
+// language/region bias, pacing requests through the provider's rate
+// limiter, and retrying transient failures with exponential backoff.
+func (g *GoogleProvider) query(ctx context.Context, values url.Values, config requestConfig) (Results, error) {
+	if config.language != "" {
+		values.Set("language", config.language)
+	}
+	if config.region != "" {
+		values.Set("region", config.region)
+	}
+
+	var requestURL string
+	switch g.authScheme {
+	case AuthSchemeWork:
+		values.Set("client", g.clientID)
+		if g.channel != "" {
+			values.Set("channel", g.channel)
+		}
+		requestURL = googleGeocodeHost + g.sign(googleGeocodePath+"?"+values.Encode())
+	default:
+		values.Set("key", g.apiKey)
+		requestURL = googleGeocodeURL + "?" + values.Encode()
+	}
+
+	// If the daily query limit has been reached, return an error until it expires.
+	if reached, expiration := g.dailyLimitReached(); reached {
+		return Results{}, &GeocodeError{
+			Status:       "OVER_QUERY_LIMIT",
+			ErrorMessage: fmt.Sprintf("the daily request quota is exceeded; it resets at %v", expiration),
+			URL:          redactURL(requestURL),
+			sentinel:     ErrOverDailyLimit,
+		}
+	}
+
+	var (
+		results             Results
+		httpErr             error
+		unknownErrorRetried bool
+	)
+	for attempt := 0; attempt <= g.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, attempt); err != nil {
+				return Results{}, err
+			}
+		}
+
+		if err := g.limiter.Wait(ctx); err != nil {
+			return Results{}, fmt.Errorf("error waiting for rate limiter: <%v>", err)
+		}
+
+		results = Results{}
+		statusCode, err := g.doRequest(ctx, requestURL, &results)
+		if err != nil {
+			httpErr = err
+			if statusCode >= http.StatusInternalServerError {
+				continue
+			}
+			return Results{}, err
+		}
+		httpErr = nil
+
+		// UNKNOWN_ERROR gets exactly one retry, as Google's documentation
+		// recommends, regardless of MaxRetries. An OVER_QUERY_LIMIT that is
+		// a short-term rate overage (not the daily quota) is worth retrying
+		// for the full MaxRetries budget. If retries run out while the status is still
+		// one of these, the switch below reports it through the usual
+		// GeocodeError path.
+		if results.Status == "UNKNOWN_ERROR" {
+			if unknownErrorRetried {
+				break
+			}
+			unknownErrorRetried = true
+			continue
+		}
+		if !retryableStatus(results.Status, results.ErrorMessage) {
+			break
+		}
+	}
+
+	if httpErr != nil {
+		return Results{}, fmt.Errorf("error geocoding after %d attempts: <%w>", g.maxRetries+1, httpErr)
+	}
+
+	switch results.Status {
+	case "OK":
+		return results, nil
+	case "OVER_QUERY_LIMIT":
+		geocodeErr := newGeocodeError(results.Status, results.ErrorMessage, requestURL)
+		if errors.Is(geocodeErr, ErrOverDailyLimit) {
+			g.setDailyLimitReached()
+		}
+		return Results{}, geocodeErr
+	default:
+		return Results{}, newGeocodeError(results.Status, results.ErrorMessage, requestURL)
+	}
+}
+
+// retryableStatus reports whether status and errorMessage describe a
+// short-term rate overage worth retrying for the full MaxRetries budget. A
+// daily-quota OVER_QUERY_LIMIT is not retried. UNKNOWN_ERROR is also
+// retried, but capped at a single attempt rather than using this budget; see
+// query.
+func retryableStatus(status, errorMessage string) bool {
+	return status == "OVER_QUERY_LIMIT" && !isDailyQuotaMessage(errorMessage)
+}
+
+// doRequest issues a single HTTP request to requestURL, decoding the
+// response into results. It returns the HTTP status code so callers can
+// tell a retryable 5xx apart from a terminal failure.
+func (g *GoogleProvider) doRequest(ctx context.Context, requestURL string, results *Results) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error building geocode request: <%v>", err)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error geocoding: <%v>", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("error reading geocoder result: <%v>", err)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp.StatusCode, fmt.Errorf("error geocoding: unexpected status %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(body, results); err != nil {
+		return resp.StatusCode, fmt.Errorf("error unmarshaling geocoder result: <%v>", err)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// dailyLimitReached reports whether the daily query limit is still in
+// effect, clearing it if it has expired.
+func (g *GoogleProvider) dailyLimitReached() (bool, time.Time) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if !g.queryLimitReached {
+		return false, time.Time{}
+	}
+	if time.Now().After(g.queryLimitExpiration) {
+		g.queryLimitReached = false
+		return false, time.Time{}
+	}
+
+	return true, g.queryLimitExpiration
+}
+
+// setDailyLimitReached marks the daily query limit as exceeded until
+// midnight Pacific time, returning the expiration.
+func (g *GoogleProvider) setDailyLimitReached() time.Time {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	pacificTimezone, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		panic(fmt.Sprintf("could not find the timezone for 'America/Los_Angeles' which is needed to set limit expiration (%v)", err))
+	}
+
+	beginningOfDayPacific, err := time.ParseInLocation("Jan 2 2006", time.Now().In(pacificTimezone).Format("Jan 2 2006"), pacificTimezone)
+	if err != nil {
+		panic(fmt.Sprintf("could not parse date when calculating query limit expiration (%v)", err))
+	}
+
+	g.queryLimitReached = true
+	g.queryLimitExpiration = beginningOfDayPacific.Add(time.Hour * 24)
+
+	return g.queryLimitExpiration
+}
+
+// sleepBackoff sleeps for an exponentially increasing delay based on
+// attempt, or returns ctx's error if it's canceled first.
+func sleepBackoff(ctx context.Context, attempt int) error {
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt-1))
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sign signs urlPath (everything from "/maps/..." including the query
+// string) as required by Google Maps APIs for Work, returning urlPath with
+// "&signature=<sig>" appended. See:
+// https://developers.google.com/maps/documentation/geocoding/get-api-key#premium-auth
+func (g *GoogleProvider) sign(urlPath string) string {
+	mac := hmac.New(sha1.New, g.privateKey)
+	mac.Write([]byte(urlPath))
+	signature := base64.URLEncoding.EncodeToString(mac.Sum(nil))
+
+	return urlPath + "&signature=" + signature
+}
@@ -0,0 +1,127 @@
+package googlegeocode
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// redirectTransport sends every request to target instead of its original
+// host, so tests can point GoogleProvider's hardcoded Google URL at an
+// httptest.Server.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	redirected := req.Clone(req.Context())
+	redirected.URL.Scheme = t.target.Scheme
+	redirected.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(redirected)
+}
+
+// TestGoogleProviderOverQueryLimitDaily verifies that an OVER_QUERY_LIMIT
+// response whose error_message names the daily quota both surfaces
+// ErrOverDailyLimit and short-circuits later calls without hitting the API
+// again.
+func TestGoogleProviderOverQueryLimitDaily(t *testing.T) {
+	var requestCount int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.Write([]byte(`{"status":"OVER_QUERY_LIMIT","error_message":"You have exceeded your daily request quota for this API.","results":[]}`))
+	}))
+	defer srv.Close()
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+
+	g, err := newGoogleProvider(Options{
+		APIKey:     "test",
+		HTTPClient: &http.Client{Transport: redirectTransport{target: target}},
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("newGoogleProvider() error: %v", err)
+	}
+
+	_, err = g.Geocode(context.Background(), "anywhere")
+	if !errors.Is(err, ErrOverDailyLimit) {
+		t.Fatalf("Geocode() error = %v, want wrapping ErrOverDailyLimit", err)
+	}
+	firstCallRequests := requestCount
+	if firstCallRequests == 0 {
+		t.Fatalf("requestCount = 0, want at least 1")
+	}
+
+	if _, err := g.Geocode(context.Background(), "anywhere"); !errors.Is(err, ErrOverDailyLimit) {
+		t.Fatalf("second Geocode() error = %v, want wrapping ErrOverDailyLimit", err)
+	}
+	if requestCount != firstCallRequests {
+		t.Fatalf("requestCount = %d after second call, want still %d (should short-circuit)", requestCount, firstCallRequests)
+	}
+}
+
+// TestGoogleProviderUnknownErrorRetriesOnce verifies that an UNKNOWN_ERROR
+// response is retried exactly once, regardless of MaxRetries, as Google's
+// documentation recommends.
+func TestGoogleProviderUnknownErrorRetriesOnce(t *testing.T) {
+	var requestCount int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.Write([]byte(`{"status":"UNKNOWN_ERROR","results":[]}`))
+	}))
+	defer srv.Close()
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+
+	g, err := newGoogleProvider(Options{
+		APIKey:     "test",
+		HTTPClient: &http.Client{Transport: redirectTransport{target: target}},
+		MaxRetries: 5,
+	})
+	if err != nil {
+		t.Fatalf("newGoogleProvider() error: %v", err)
+	}
+
+	_, err = g.Geocode(context.Background(), "anywhere")
+	if !errors.Is(err, ErrUnknown) {
+		t.Fatalf("Geocode() error = %v, want wrapping ErrUnknown", err)
+	}
+	if requestCount != 2 {
+		t.Errorf("requestCount = %d, want 2 (one retry, regardless of MaxRetries=5)", requestCount)
+	}
+}
+
+// TestGoogleProviderSign verifies signing against the sample client ID,
+// private key, and URL published in Google's Maps APIs for Work
+// documentation.
+func TestGoogleProviderSign(t *testing.T) {
+	g := &GoogleProvider{
+		authScheme: AuthSchemeWork,
+		clientID:   "clientID",
+	}
+
+	privateKey, err := base64.URLEncoding.DecodeString("vNIXE0xscrmjlyV-12Nj_BvUPaw=")
+	if err != nil {
+		t.Fatalf("decoding sample private key: %v", err)
+	}
+	g.privateKey = privateKey
+
+	const urlPath = "/maps/api/geocode/json?address=New+York&client=clientID"
+	const wantSignature = "chaRF2hTJKOScPr-RQCEhZbSzIE="
+
+	got := g.sign(urlPath)
+	want := urlPath + "&signature=" + wantSignature
+	if got != want {
+		t.Errorf("sign(%q) = %q, want %q", urlPath, got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package googlegeocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const defaultNominatimEndpoint = "https://nominatim.openstreetmap.org"
+
+// NominatimProvider queries the OpenStreetMap Nominatim API and translates
+// its responses into Results.
+type NominatimProvider struct {
+	httpClient *http.Client
+	endpoint   string
+	language   string
+}
+
+func newNominatimProvider(opts Options) *NominatimProvider {
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = defaultNominatimEndpoint
+	}
+
+	return &NominatimProvider{
+		httpClient: opts.HTTPClient,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		language:   opts.Language,
+	}
+}
+
+// Geocode looks up the coordinates for address using the Nominatim API.
+// Only WithLanguage is honored; other opts bias parameters the Nominatim
+// API doesn't support and are ignored.
+func (n *NominatimProvider) Geocode(ctx context.Context, address string, opts ...Option) (Results, error) {
+	values := url.Values{}
+	values.Set("q", address)
+
+	return n.query(ctx, n.endpoint+"/search", values, opts)
+}
+
+// ReverseGeocode looks up the address at the given coordinates using the
+// Nominatim API. Only WithLanguage is honored; other opts filter on
+// parameters the Nominatim API doesn't support and are ignored.
+func (n *NominatimProvider) ReverseGeocode(ctx context.Context, lat, lng float64, opts ...Option) (Results, error) {
+	values := url.Values{}
+	values.Set("lat", fmt.Sprintf("%v", lat))
+	values.Set("lon", fmt.Sprintf("%v", lng))
+
+	return n.query(ctx, n.endpoint+"/reverse", values, opts)
+}
+
+func (n *NominatimProvider) query(ctx context.Context, requestURL string, values url.Values, opts []Option) (Results, error) {
+	values.Set("format", "json")
+	config := buildRequestConfig(n.language, "", opts)
+	if config.language != "" {
+		values.Set("accept-language", config.language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL+"?"+values.Encode(), nil)
+	if err != nil {
+		return Results{}, fmt.Errorf("error building nominatim request: <%v>", err)
+	}
+	// Nominatim's usage policy requires a distinguishing User-Agent.
+	req.Header.Set("User-Agent", "googlegeocode (https://github.com/joshansen/googlegeocode)")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return Results{}, fmt.Errorf("error geocoding with nominatim: <%v>", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Results{}, fmt.Errorf("error reading nominatim result: <%v>", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Results{}, fmt.Errorf("error geocoding with nominatim: unexpected status %s: %s", resp.Status, body)
+	}
+
+	places, err := decodeNominatimPlaces(body)
+	if err != nil {
+		return Results{}, fmt.Errorf("error unmarshaling nominatim result: <%v>", err)
+	}
+
+	return nominatimPlacesToResults(places), nil
+}
+
+// nominatimPlace is a single result returned by Nominatim's /search and
+// /reverse endpoints. The reverse endpoint returns a single object rather
+// than an array; decodeNominatimPlaces normalizes both shapes.
+type nominatimPlace struct {
+	PlaceID     int64  `json:"place_id"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+// nominatimErrorResponse is the shape Nominatim uses to report a failure,
+// e.g. {"error":"Unable to geocode"}.
+type nominatimErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// decodeNominatimPlaces handles both the array response from /search and
+// the single-object response from /reverse, returning an error for either
+// an explicit {"error": ...} body or any object that doesn't look like a
+// place (missing place_id and lat/lon).
+func decodeNominatimPlaces(body []byte) ([]nominatimPlace, error) {
+	var places []nominatimPlace
+	if err := json.Unmarshal(body, &places); err == nil {
+		return places, nil
+	}
+
+	var errResp nominatimErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		return nil, fmt.Errorf("nominatim error: %s", errResp.Error)
+	}
+
+	var place nominatimPlace
+	if err := json.Unmarshal(body, &place); err != nil {
+		return nil, err
+	}
+	if place.PlaceID == 0 && place.Lat == "" && place.Lon == "" {
+		return nil, fmt.Errorf("nominatim: unrecognized response: %s", body)
+	}
+
+	return []nominatimPlace{place}, nil
+}
+
+// nominatimPlacesToResults translates Nominatim places into the shared
+// Results shape.
+func nominatimPlacesToResults(places []nominatimPlace) Results {
+	var results Results
+
+	if len(places) == 0 {
+		results.Status = "ZERO_RESULTS"
+		return results
+	}
+	results.Status = "OK"
+
+	for _, place := range places {
+		lat, _ := strconv.ParseFloat(place.Lat, 64)
+		lng, _ := strconv.ParseFloat(place.Lon, 64)
+
+		var r Result
+
+		r.FormattedAddress = place.DisplayName
+		r.Geometry.Location.Lat = lat
+		r.Geometry.Location.Lng = lng
+		r.PlaceID = fmt.Sprintf("osm:%d", place.PlaceID)
+		if place.Type != "" {
+			r.Types = []string{place.Type}
+		}
+
+		results.Results = append(results.Results, r)
+	}
+
+	return results
+}
@@ -0,0 +1,79 @@
+package googlegeocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newNominatimProviderForTest(t *testing.T, handler http.HandlerFunc) *NominatimProvider {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return newNominatimProvider(Options{
+		Endpoint:   srv.URL,
+		HTTPClient: srv.Client(),
+	})
+}
+
+func TestNominatimProviderGeocode(t *testing.T) {
+	n := newNominatimProviderForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"place_id":123,"lat":"40.7","lon":"-74.0","display_name":"New York, NY, USA","type":"city"}]`))
+	})
+
+	results, err := n.Geocode(context.Background(), "New York")
+	if err != nil {
+		t.Fatalf("Geocode() error: %v", err)
+	}
+	if results.Status != "OK" || len(results.Results) != 1 {
+		t.Fatalf("Geocode() = %+v, want one OK result", results)
+	}
+	if got := results.Results[0].FormattedAddress; got != "New York, NY, USA" {
+		t.Errorf("FormattedAddress = %q, want %q", got, "New York, NY, USA")
+	}
+}
+
+func TestNominatimProviderZeroResults(t *testing.T) {
+	n := newNominatimProviderForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+
+	results, err := n.Geocode(context.Background(), "nowhere")
+	if err != nil {
+		t.Fatalf("Geocode() error: %v", err)
+	}
+	if results.Status != "ZERO_RESULTS" {
+		t.Errorf("Status = %q, want ZERO_RESULTS", results.Status)
+	}
+}
+
+// TestNominatimProviderErrorBody verifies that an {"error": ...} response
+// body is reported as an error rather than as a bogus zero-value result.
+func TestNominatimProviderErrorBody(t *testing.T) {
+	n := newNominatimProviderForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":"Unable to geocode"}`))
+	})
+
+	_, err := n.Geocode(context.Background(), "anywhere")
+	if err == nil {
+		t.Fatal("Geocode() error = nil, want an error for an {\"error\": ...} body")
+	}
+}
+
+// TestNominatimProviderHTTPError verifies that a non-200 response (e.g. a
+// rate limit or maintenance page) is reported as an error rather than
+// decoded as a place.
+func TestNominatimProviderHTTPError(t *testing.T) {
+	n := newNominatimProviderForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`<html>rate limited</html>`))
+	})
+
+	_, err := n.Geocode(context.Background(), "anywhere")
+	if err == nil {
+		t.Fatal("Geocode() error = nil, want an error for a 429 response")
+	}
+}
@@ -0,0 +1,120 @@
+package googlegeocode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// requestConfig holds the per-call parameters gathered from a set of
+// Options passed to Geocode or ReverseGeocode.
+type requestConfig struct {
+	components   map[string]string
+	bounds       *Rectangle
+	region       string
+	language     string
+	resultType   []string
+	locationType []string
+}
+
+// Option configures a single Geocode or ReverseGeocode call. Unlike the
+// Language and Region fields on Options, which bias every call a Geocoder
+// makes, an Option only affects the call it's passed to.
+type Option func(*requestConfig)
+
+// WithComponents restricts results to those matching every component
+// filter, e.g. WithComponents(map[string]string{"country": "US", "postal_code": "94040"}).
+// Only honored by ProviderGoogle.
+func WithComponents(components map[string]string) Option {
+	return func(c *requestConfig) {
+		c.components = components
+	}
+}
+
+// WithBounds biases results toward the given viewport. Only honored by
+// ProviderGoogle.
+func WithBounds(bounds Rectangle) Option {
+	return func(c *requestConfig) {
+		c.bounds = &bounds
+	}
+}
+
+// WithRegion biases results toward a ccTLD region, e.g. "us", overriding the
+// Geocoder's default Region for this call. Only honored by ProviderGoogle.
+func WithRegion(region string) Option {
+	return func(c *requestConfig) {
+		c.region = region
+	}
+}
+
+// WithLanguage requests results in language, e.g. "en", overriding the
+// Geocoder's default Language for this call.
+func WithLanguage(language string) Option {
+	return func(c *requestConfig) {
+		c.language = language
+	}
+}
+
+// WithResultType restricts ReverseGeocode results to the given place types,
+// e.g. WithResultType("street_address", "locality"). Only honored by
+// ProviderGoogle.
+func WithResultType(types ...string) Option {
+	return func(c *requestConfig) {
+		c.resultType = types
+	}
+}
+
+// WithLocationType restricts ReverseGeocode results to the given location
+// types, e.g. WithLocationType("ROOFTOP"). Only honored by ProviderGoogle.
+func WithLocationType(types ...string) Option {
+	return func(c *requestConfig) {
+		c.locationType = types
+	}
+}
+
+// LatLng is a pair of geographic coordinates.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// Rectangle is a viewport used to bias results, expressed as its southwest
+// and northeast corners.
+type Rectangle struct {
+	Southwest LatLng
+	Northeast LatLng
+}
+
+// String formats the Rectangle as Google's bounds parameter expects:
+// "southwest_lat,southwest_lng|northeast_lat,northeast_lng".
+func (r Rectangle) String() string {
+	return fmt.Sprintf("%v,%v|%v,%v", r.Southwest.Lat, r.Southwest.Lng, r.Northeast.Lat, r.Northeast.Lng)
+}
+
+// buildRequestConfig folds opts over the Geocoder's default language/region.
+func buildRequestConfig(defaultLanguage, defaultRegion string, opts []Option) requestConfig {
+	config := requestConfig{
+		language: defaultLanguage,
+		region:   defaultRegion,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// componentsString joins a components filter as Google expects:
+// "country:US|postal_code:94040". Keys are sorted for deterministic output.
+func componentsString(components map[string]string) string {
+	keys := make([]string, 0, len(components))
+	for k := range components {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+":"+components[k])
+	}
+	return strings.Join(parts, "|")
+}
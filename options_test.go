@@ -0,0 +1,23 @@
+package googlegeocode
+
+import "testing"
+
+func TestComponentsString(t *testing.T) {
+	got := componentsString(map[string]string{"postal_code": "94040", "country": "US"})
+	want := "country:US|postal_code:94040"
+	if got != want {
+		t.Errorf("componentsString(...) = %q, want %q", got, want)
+	}
+}
+
+func TestRectangleString(t *testing.T) {
+	r := Rectangle{
+		Southwest: LatLng{Lat: 40.477399, Lng: -74.259090},
+		Northeast: LatLng{Lat: 40.917577, Lng: -73.700272},
+	}
+	got := r.String()
+	want := "40.477399,-74.25909|40.917577,-73.700272"
+	if got != want {
+		t.Errorf("Rectangle.String() = %q, want %q", got, want)
+	}
+}
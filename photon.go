@@ -0,0 +1,152 @@
+package googlegeocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultPhotonEndpoint = "https://photon.komoot.io"
+
+// PhotonProvider queries a Photon server (https://photon.komoot.io, or a
+// self-hosted instance) and translates its GeoJSON responses into Results.
+type PhotonProvider struct {
+	httpClient *http.Client
+	endpoint   string
+	language   string
+}
+
+func newPhotonProvider(opts Options) *PhotonProvider {
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = defaultPhotonEndpoint
+	}
+
+	return &PhotonProvider{
+		httpClient: opts.HTTPClient,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		language:   opts.Language,
+	}
+}
+
+// Geocode looks up the coordinates for address using the Photon API. Only
+// WithLanguage is honored; other opts bias parameters the Photon API
+// doesn't support and are ignored.
+func (p *PhotonProvider) Geocode(ctx context.Context, address string, opts ...Option) (Results, error) {
+	values := url.Values{}
+	values.Set("q", address)
+
+	return p.query(ctx, p.endpoint+"/api/", values, opts)
+}
+
+// ReverseGeocode looks up the address at the given coordinates using the
+// Photon API. Only WithLanguage is honored; other opts filter on parameters
+// the Photon API doesn't support and are ignored.
+func (p *PhotonProvider) ReverseGeocode(ctx context.Context, lat, lng float64, opts ...Option) (Results, error) {
+	values := url.Values{}
+	values.Set("lat", fmt.Sprintf("%v", lat))
+	values.Set("lon", fmt.Sprintf("%v", lng))
+
+	return p.query(ctx, p.endpoint+"/reverse", values, opts)
+}
+
+func (p *PhotonProvider) query(ctx context.Context, requestURL string, values url.Values, opts []Option) (Results, error) {
+	config := buildRequestConfig(p.language, "", opts)
+	if config.language != "" {
+		values.Set("lang", config.language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL+"?"+values.Encode(), nil)
+	if err != nil {
+		return Results{}, fmt.Errorf("error building photon request: <%v>", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return Results{}, fmt.Errorf("error geocoding with photon: <%v>", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Results{}, fmt.Errorf("error reading photon result: <%v>", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Results{}, fmt.Errorf("error geocoding with photon: unexpected status %s: %s", resp.Status, body)
+	}
+
+	var featureCollection photonFeatureCollection
+	if err := json.Unmarshal(body, &featureCollection); err != nil {
+		return Results{}, fmt.Errorf("error unmarshaling photon result: <%v>", err)
+	}
+
+	return featureCollection.toResults(), nil
+}
+
+// photonFeatureCollection is the GeoJSON response returned by Photon.
+type photonFeatureCollection struct {
+	Features []struct {
+		Geometry struct {
+			Coordinates [2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			OSMID    int64  `json:"osm_id"`
+			OSMKey   string `json:"osm_key"`
+			OSMValue string `json:"osm_value"`
+			Name     string `json:"name"`
+			Street   string `json:"street"`
+			City     string `json:"city"`
+			State    string `json:"state"`
+			Country  string `json:"country"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// toResults translates a Photon response into the shared Results shape.
+func (f photonFeatureCollection) toResults() Results {
+	var results Results
+
+	if len(f.Features) == 0 {
+		results.Status = "ZERO_RESULTS"
+		return results
+	}
+	results.Status = "OK"
+
+	for _, feature := range f.Features {
+		var r Result
+
+		r.FormattedAddress = strings.Join(nonEmpty(
+			feature.Properties.Name,
+			feature.Properties.Street,
+			feature.Properties.City,
+			feature.Properties.State,
+			feature.Properties.Country,
+		), ", ")
+		r.Geometry.Location.Lng = feature.Geometry.Coordinates[0]
+		r.Geometry.Location.Lat = feature.Geometry.Coordinates[1]
+		r.PlaceID = fmt.Sprintf("osm:%d", feature.Properties.OSMID)
+		if feature.Properties.OSMValue != "" {
+			r.Types = []string{feature.Properties.OSMValue}
+		}
+
+		results.Results = append(results.Results, r)
+	}
+
+	return results
+}
+
+// nonEmpty returns the subset of values that aren't empty strings.
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
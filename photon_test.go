@@ -0,0 +1,51 @@
+package googlegeocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newPhotonProviderForTest(t *testing.T, handler http.HandlerFunc) *PhotonProvider {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return newPhotonProvider(Options{
+		Endpoint:   srv.URL,
+		HTTPClient: srv.Client(),
+	})
+}
+
+func TestPhotonProviderGeocode(t *testing.T) {
+	p := newPhotonProviderForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"features":[{"geometry":{"coordinates":[-74.0,40.7]},"properties":{"osm_id":1,"osm_value":"city","name":"New York","country":"USA"}}]}`))
+	})
+
+	results, err := p.Geocode(context.Background(), "New York")
+	if err != nil {
+		t.Fatalf("Geocode() error: %v", err)
+	}
+	if results.Status != "OK" || len(results.Results) != 1 {
+		t.Fatalf("Geocode() = %+v, want one OK result", results)
+	}
+	if got := results.Results[0].Geometry.Location.Lat; got != 40.7 {
+		t.Errorf("Lat = %v, want 40.7", got)
+	}
+}
+
+// TestPhotonProviderHTTPError verifies that a non-200 response is reported
+// as an error rather than decoded as a (likely empty) feature collection.
+func TestPhotonProviderHTTPError(t *testing.T) {
+	p := newPhotonProviderForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<html>maintenance</html>`))
+	})
+
+	_, err := p.Geocode(context.Background(), "anywhere")
+	if err == nil {
+		t.Fatal("Geocode() error = nil, want an error for a 503 response")
+	}
+}